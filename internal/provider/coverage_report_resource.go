@@ -0,0 +1,400 @@
+// Copyright (c) Brendan Thompson
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CoverageReportResource{}
+
+func NewCoverageReportResource() resource.Resource {
+	return &CoverageReportResource{}
+}
+
+// CoverageReportResource defines the resource implementation.
+type CoverageReportResource struct {
+}
+
+// CoverageReportResourceModel describes the resource data model.
+type CoverageReportResourceModel struct {
+	Id                types.String  `tfsdk:"id"`
+	ExamplesDirectory types.String  `tfsdk:"examples_directory"`
+	TestsDirectory    types.String  `tfsdk:"tests_directory"`
+	Filter            types.String  `tfsdk:"filter"`
+	TestFormat        types.String  `tfsdk:"test_format"`
+	Parser            types.String  `tfsdk:"parser"`
+	ExampleDepth      types.Int64   `tfsdk:"example_depth"`
+	Strict            types.Bool    `tfsdk:"strict"`
+	OutputPath        types.String  `tfsdk:"output_path"`
+	Format            types.String  `tfsdk:"format"`
+	FailOnMissing     types.Bool    `tfsdk:"fail_on_missing"`
+	TotalExamples     types.Int64   `tfsdk:"total_examples"`
+	CoveredExamples   types.Int64   `tfsdk:"covered_examples"`
+	CoveragePercent   types.Float64 `tfsdk:"coverage_percent"`
+}
+
+func (r *CoverageReportResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_coverage_report"
+}
+
+func (r *CoverageReportResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Writes a structured report of example test coverage to disk, for consumption by CI dashboards and PR comments.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID",
+				Computed:            true,
+			},
+			"examples_directory": schema.StringAttribute{
+				MarkdownDescription: "Filepath to the examples directory for the module.",
+				Required:            true,
+			},
+			"tests_directory": schema.StringAttribute{
+				MarkdownDescription: "Filepath to the tests directory for the module.",
+				Required:            true,
+			},
+			"filter": schema.StringAttribute{
+				MarkdownDescription: "Filter to use to find tests responsible for validating the examples.",
+				Required:            true,
+			},
+			"test_format": schema.StringAttribute{
+				MarkdownDescription: "How test coverage is discovered: `legacy`, `tftest`, or `auto`. Defaults to `auto`.",
+				Optional:            true,
+			},
+			"parser": schema.StringAttribute{
+				MarkdownDescription: "Engine used to find module `source` attributes in `legacy`/`auto` coverage files: `hcl` or `regex`. Defaults to `hcl`.",
+				Optional:            true,
+			},
+			"example_depth": schema.Int64Attribute{
+				MarkdownDescription: "How many directory levels below `examples_directory` to search for examples. `0` (the default) means unlimited depth.",
+				Optional:            true,
+			},
+			"strict": schema.BoolAttribute{
+				MarkdownDescription: "When `true`, a file or directory that can't be read fails the write. When `false` (the default), it's skipped.",
+				Optional:            true,
+			},
+			"output_path": schema.StringAttribute{
+				MarkdownDescription: "Filepath the report is written to.",
+				Required:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Report format: `json`, `junit`, or `markdown`.",
+				Required:            true,
+			},
+			"fail_on_missing": schema.BoolAttribute{
+				MarkdownDescription: "Fail the apply if any example is missing test coverage. Defaults to `false`.",
+				Optional:            true,
+			},
+			"total_examples": schema.Int64Attribute{
+				MarkdownDescription: "Total number of examples discovered.",
+				Computed:            true,
+			},
+			"covered_examples": schema.Int64Attribute{
+				MarkdownDescription: "Number of examples with at least one covering test.",
+				Computed:            true,
+			},
+			"coverage_percent": schema.Float64Attribute{
+				MarkdownDescription: "Percentage of examples with at least one covering test.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *CoverageReportResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+}
+
+func (r *CoverageReportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CoverageReportResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	writeCoverageReport(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CoverageReportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CoverageReportResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read only confirms the report this resource wrote is still present; it
+	// must not rewrite output_path or evaluate fail_on_missing, since those
+	// are side effects a read-only plan/refresh shouldn't have.
+	if _, err := os.Stat(data.OutputPath.ValueString()); err != nil {
+		if os.IsNotExist(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Unable to read coverage report", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CoverageReportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CoverageReportResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	writeCoverageReport(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CoverageReportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CoverageReportResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := os.Remove(data.OutputPath.ValueString()); err != nil && !os.IsNotExist(err) {
+		resp.Diagnostics.AddError("Unable to remove coverage report", err.Error())
+	}
+}
+
+// coverageReport is the schema-versioned report written to output_path.
+type coverageReport struct {
+	SchemaVersion   int                              `json:"schema_version"`
+	TotalExamples   int                              `json:"total_examples"`
+	CoveredExamples int                              `json:"covered_examples"`
+	CoveragePercent float64                          `json:"coverage_percent"`
+	MissingExamples []string                         `json:"missing_examples"`
+	Coverage        map[string][]coverageReportEntry `json:"coverage"`
+}
+
+// coverageReportEntry is a single test run covering an example, in a form
+// safe to marshal directly to JSON/XML.
+type coverageReportEntry struct {
+	File    string `json:"file"`
+	RunName string `json:"run_name,omitempty"`
+}
+
+// writeCoverageReport re-derives coverage for data and writes it to
+// output_path in the requested format, updating the computed attributes on
+// data in place. Called by Create and Update only; Read must not write to
+// disk or fail on fail_on_missing.
+func writeCoverageReport(ctx context.Context, data *CoverageReportResourceModel, diags *diag.Diagnostics) {
+	data.Id = types.StringValue(data.OutputPath.ValueString())
+
+	examplesDirectory := data.ExamplesDirectory.ValueString()
+	testsDirectory := data.TestsDirectory.ValueString()
+	filter := data.Filter.ValueString()
+
+	testFormat := data.TestFormat.ValueString()
+	if testFormat == "" {
+		testFormat = "auto"
+	}
+
+	parserMode := data.Parser.ValueString()
+	if parserMode == "" {
+		parserMode = "hcl"
+	}
+
+	exampleDepth := int(data.ExampleDepth.ValueInt64())
+
+	strict := data.Strict.ValueBool()
+
+	missing, coverage, examples, _ := findMissingTests(examplesDirectory, testsDirectory, filter, testFormat, parserMode, exampleDepth, nil, strict, ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	slices.Sort(missing)
+
+	total := len(examples)
+
+	covered := 0
+	for _, example := range examples {
+		if _, ok := coverage[example]; ok {
+			covered++
+		}
+	}
+
+	var percent float64
+	if total > 0 {
+		percent = float64(covered) / float64(total) * 100
+	}
+
+	data.TotalExamples = types.Int64Value(int64(total))
+	data.CoveredExamples = types.Int64Value(int64(covered))
+	data.CoveragePercent = types.Float64Value(percent)
+
+	report := coverageReport{
+		SchemaVersion:   1,
+		TotalExamples:   total,
+		CoveredExamples: covered,
+		CoveragePercent: percent,
+		MissingExamples: missing,
+		Coverage:        coverageReportEntries(coverage),
+	}
+
+	var (
+		body []byte
+		err  error
+	)
+
+	switch data.Format.ValueString() {
+	case "json":
+		body, err = renderCoverageReportJSON(report)
+	case "junit":
+		body, err = renderCoverageReportJUnit(report)
+	case "markdown":
+		body = renderCoverageReportMarkdown(report)
+	default:
+		diags.AddError("Invalid format", fmt.Sprintf("format must be one of \"json\", \"junit\", or \"markdown\", got %q.", data.Format.ValueString()))
+		return
+	}
+
+	if err != nil {
+		diags.AddError("Unable to render coverage report", err.Error())
+		return
+	}
+
+	if err := os.WriteFile(data.OutputPath.ValueString(), body, 0o644); err != nil {
+		diags.AddError("Unable to write coverage report", err.Error())
+		return
+	}
+
+	if data.FailOnMissing.ValueBool() && len(missing) > 0 {
+		diags.AddError(
+			"Examples missing test coverage",
+			fmt.Sprintf("%d example(s) are missing test coverage: %s", len(missing), strings.Join(missing, ", ")),
+		)
+	}
+}
+
+// coverageReportEntries flattens the internal run coverage representation
+// into the plain, marshalable shape used by the report.
+func coverageReportEntries(coverage map[string][]tftestRunCoverage) map[string][]coverageReportEntry {
+	entries := make(map[string][]coverageReportEntry, len(coverage))
+
+	for example, runs := range coverage {
+		for _, run := range runs {
+			entries[example] = append(entries[example], coverageReportEntry{File: run.file, RunName: run.runName})
+		}
+	}
+
+	return entries
+}
+
+func renderCoverageReportJSON(report coverageReport) ([]byte, error) {
+	body, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(body, '\n'), nil
+}
+
+// junitTestsuite is a minimal JUnit XML testsuite, enough for CI systems
+// (CircleCI, GitHub Actions) to render one test result per example.
+type junitTestsuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Testcases []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+func renderCoverageReportJUnit(report coverageReport) ([]byte, error) {
+	missing := make(map[string]bool, len(report.MissingExamples))
+	for _, example := range report.MissingExamples {
+		missing[example] = true
+	}
+
+	examples := make([]string, 0, report.TotalExamples)
+	for example := range report.Coverage {
+		examples = append(examples, example)
+	}
+	examples = append(examples, report.MissingExamples...)
+	slices.Sort(examples)
+
+	suite := junitTestsuite{
+		Name:     "examples_coverage",
+		Tests:    report.TotalExamples,
+		Failures: len(report.MissingExamples),
+	}
+
+	for _, example := range examples {
+		testcase := junitTestcase{Name: example}
+		if missing[example] {
+			testcase.Failure = &junitFailure{Message: "no test run covers this example"}
+		}
+
+		suite.Testcases = append(suite.Testcases, testcase)
+	}
+
+	body, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
+
+func renderCoverageReportMarkdown(report coverageReport) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Example Coverage\n\n")
+	fmt.Fprintf(&b, "%d/%d examples covered (%.1f%%)\n\n", report.CoveredExamples, report.TotalExamples, report.CoveragePercent)
+
+	if len(report.MissingExamples) > 0 {
+		b.WriteString("### Missing coverage\n\n")
+		for _, example := range report.MissingExamples {
+			fmt.Fprintf(&b, "- %s\n", example)
+		}
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String())
+}
@@ -5,6 +5,8 @@ package provider
 import (
 	"bufio"
 	"context"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,11 +15,16 @@ import (
 
 	// "net/http"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -33,11 +40,31 @@ type ExamplesValidationDataSource struct {
 
 // ExamplesValidationDataSourceModel describes the data source data model.
 type ExamplesValidationDataSourceModel struct {
-	Id                types.String `tfsdk:"id"`
-	ExamplesDirectory types.String `tfsdk:"examples_directory"`
-	TestsDirectory    types.String `tfsdk:"tests_directory"`
-	Filter            types.String `tfsdk:"filter"`
-	MissingTests      types.List   `tfsdk:"missing_tests"`
+	Id                   types.String `tfsdk:"id"`
+	ExamplesDirectory    types.String `tfsdk:"examples_directory"`
+	TestsDirectory       types.String `tfsdk:"tests_directory"`
+	Filter               types.String `tfsdk:"filter"`
+	TestFormat           types.String `tfsdk:"test_format"`
+	Parser               types.String `tfsdk:"parser"`
+	ExampleDepth         types.Int64  `tfsdk:"example_depth"`
+	RequireMocks         types.Bool   `tfsdk:"require_mocks"`
+	MockFilter           types.String `tfsdk:"mock_filter"`
+	Strict               types.Bool   `tfsdk:"strict"`
+	MissingTests         types.List   `tfsdk:"missing_tests"`
+	CoverageMap          types.Map    `tfsdk:"coverage_map"`
+	ExamplesWithoutMocks types.List   `tfsdk:"examples_without_mocks"`
+	ScanErrors           types.List   `tfsdk:"scan_errors"`
+}
+
+// CoverageMapEntryModel describes a single test run that exercises an example.
+type CoverageMapEntryModel struct {
+	File    types.String `tfsdk:"file"`
+	RunName types.String `tfsdk:"run_name"`
+}
+
+var coverageMapEntryAttrTypes = map[string]attr.Type{
+	"file":     types.StringType,
+	"run_name": types.StringType,
 }
 
 func (d *ExamplesValidationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -66,11 +93,50 @@ func (d *ExamplesValidationDataSource) Schema(ctx context.Context, req datasourc
 				MarkdownDescription: "Filter to use to find tests responsible for validating the examples.",
 				Required:            true,
 			},
+			"test_format": schema.StringAttribute{
+				MarkdownDescription: "How test coverage is discovered: `legacy` scans files matching `filter` for module `source` attributes that resolve inside `examples_directory` (see `parser`), `tftest` parses native Terraform test files (`*.tftest.hcl`) and walks their `run` blocks, `auto` does both. Defaults to `auto`.",
+				Optional:            true,
+			},
+			"parser": schema.StringAttribute{
+				MarkdownDescription: "Engine used to find module `source` attributes in `legacy`/`auto` coverage files: `hcl` parses the file and walks `module` blocks at any nesting depth, `regex` uses the original line-based matcher. Defaults to `hcl`.",
+				Optional:            true,
+			},
+			"example_depth": schema.Int64Attribute{
+				MarkdownDescription: "How many directory levels below `examples_directory` to search for examples. Any directory at or above this depth that directly contains a `.tf` file is treated as an example, identified by its path relative to `examples_directory` (e.g. `aws/vpc/basic`). `0` (the default) means unlimited depth.",
+				Optional:            true,
+			},
 			"missing_tests": schema.ListAttribute{
 				MarkdownDescription: "List of example directories that are missing tests",
 				ElementType:         types.StringType,
 				Computed:            true,
 			},
+			"coverage_map": schema.MapAttribute{
+				MarkdownDescription: "Map of example name to the list of test runs that cover it, each describing the `file` it was found in and the `run_name` of the covering `run` block (`run_name` is empty for `legacy` coverage).",
+				ElementType:         types.ListType{ElemType: types.ObjectType{AttrTypes: coverageMapEntryAttrTypes}},
+				Computed:            true,
+			},
+			"require_mocks": schema.BoolAttribute{
+				MarkdownDescription: "When `true`, an example covered by at least one `tftest` run is reported in `examples_without_mocks` if none of its `tftest` runs is in a file declaring a `mock_provider` block or overrides every provider it references (via `override_resource`/`override_data`). Coverage found by `legacy` scanning is not checked. Defaults to `false`.",
+				Optional:            true,
+			},
+			"mock_filter": schema.StringAttribute{
+				MarkdownDescription: "Regular expression restricting `require_mocks` checks to providers whose name matches it. Unset checks every provider a run references.",
+				Optional:            true,
+			},
+			"examples_without_mocks": schema.ListAttribute{
+				MarkdownDescription: "List of examples covered by at least one `tftest` run, none of which satisfy `require_mocks`. Always empty when `require_mocks` is `false`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"strict": schema.BoolAttribute{
+				MarkdownDescription: "When `true`, a file or directory that can't be read fails the read. When `false` (the default), it's skipped and recorded in `scan_errors`.",
+				Optional:            true,
+			},
+			"scan_errors": schema.ListAttribute{
+				MarkdownDescription: "Files or directories that were skipped because they could not be read. Always empty when `strict` is `true`, since the first such error fails the read instead.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -100,7 +166,34 @@ func (d *ExamplesValidationDataSource) Read(ctx context.Context, req datasource.
 	testsDirectory := data.TestsDirectory.ValueString()
 	filter := data.Filter.ValueString()
 
-	missingTests := findMissingTests(examplesDirectory, testsDirectory, filter, ctx)
+	testFormat := data.TestFormat.ValueString()
+	if testFormat == "" {
+		testFormat = "auto"
+	}
+
+	parserMode := data.Parser.ValueString()
+	if parserMode == "" {
+		parserMode = "hcl"
+	}
+
+	exampleDepth := int(data.ExampleDepth.ValueInt64())
+
+	var mockFilter *regexp.Regexp
+	if v := data.MockFilter.ValueString(); v != "" {
+		compiled, err := regexp.Compile(v)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid mock_filter", err.Error())
+			return
+		}
+		mockFilter = compiled
+	}
+
+	strict := data.Strict.ValueBool()
+
+	missingTests, coverage, _, scanErrors := findMissingTests(examplesDirectory, testsDirectory, filter, testFormat, parserMode, exampleDepth, mockFilter, strict, ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	data.MissingTests, diags = types.ListValueFrom(ctx, types.StringType, missingTests)
 	resp.Diagnostics.Append(diags...)
@@ -108,6 +201,29 @@ func (d *ExamplesValidationDataSource) Read(ctx context.Context, req datasource.
 		return
 	}
 
+	data.CoverageMap, diags = coverageMapToMapValue(ctx, coverage)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var withoutMocks []string
+	if data.RequireMocks.ValueBool() {
+		withoutMocks = examplesWithoutMocks(coverage)
+	}
+
+	data.ExamplesWithoutMocks, diags = types.ListValueFrom(ctx, types.StringType, withoutMocks)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ScanErrors, diags = types.ListValueFrom(ctx, types.StringType, scanErrors)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "read a data source")
@@ -116,57 +232,505 @@ func (d *ExamplesValidationDataSource) Read(ctx context.Context, req datasource.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func findMissingTests(examplesDirectory string, testsDirectory string, filter string, ctx context.Context) []string {
+// tftestRunCoverage records that a single run block, found in file, covers example.
+// runName is empty, and hasMock and fromTFTest are false, for coverage
+// discovered via the legacy scanners (searchFile or searchFileHCL).
+type tftestRunCoverage struct {
+	example    string
+	file       string
+	runName    string
+	hasMock    bool
+	fromTFTest bool
+}
+
+// examplesWithoutMocks returns, sorted, every example covered by at least one
+// native test run (parseTFTestFile) whose covering runs all fail the
+// require_mocks check. Examples covered only by the legacy/HCL source =
+// scanner have no run block or mock_provider to evaluate, so they're
+// excluded rather than reported as missing mocks.
+func examplesWithoutMocks(coverage map[string][]tftestRunCoverage) []string {
+	var result []string
+
+	for example, runs := range coverage {
+		evaluated := false
+		mocked := false
+
+		for _, run := range runs {
+			if !run.fromTFTest {
+				continue
+			}
+
+			evaluated = true
+
+			if run.hasMock {
+				mocked = true
+				break
+			}
+		}
+
+		if evaluated && !mocked {
+			result = append(result, example)
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// coverageMapToMapValue converts the internal coverage representation into the
+// map(list(object)) shape exposed on coverage_map.
+func coverageMapToMapValue(ctx context.Context, coverage map[string][]tftestRunCoverage) (types.Map, diag.Diagnostics) {
+	entries := make(map[string][]CoverageMapEntryModel, len(coverage))
+
+	for example, runs := range coverage {
+		models := make([]CoverageMapEntryModel, 0, len(runs))
+		for _, run := range runs {
+			models = append(models, CoverageMapEntryModel{
+				File:    types.StringValue(run.file),
+				RunName: types.StringValue(run.runName),
+			})
+		}
+		entries[example] = models
+	}
+
+	return types.MapValueFrom(ctx, types.ListType{ElemType: types.ObjectType{AttrTypes: coverageMapEntryAttrTypes}}, entries)
+}
+
+func findMissingTests(examplesDirectory string, testsDirectory string, filter string, testFormat string, parserMode string, exampleDepth int, mockFilter *regexp.Regexp, strict bool, ctx context.Context, diags *diag.Diagnostics) ([]string, map[string][]tftestRunCoverage, []string, []string) {
 	var sourceFilter = `source = "./examples`
-	var examples []string
-	var tests []string
 	var missing []string
+	var scanErrors []string
+	coverage := map[string][]tftestRunCoverage{}
 
-	files, err := os.ReadDir(examplesDirectory)
+	examples, err := discoverExamples(examplesDirectory, exampleDepth, strict, diags, &scanErrors)
 	if err != nil {
-		panic(err)
+		return nil, nil, nil, scanErrors
 	}
 
-	for _, file := range files {
-		if file.IsDir() {
-			// tflog.Info(ctx, file.Name())
-			examples = append(examples, file.Name())
-		}
+	// exampleSet constrains coverage keys to examples that discoverExamples
+	// actually found, so a run/module source resolving to a nested path that
+	// isn't itself a recognized example doesn't inflate coverage accounting.
+	exampleSet := make(map[string]bool, len(examples))
+	for _, e := range examples {
+		exampleSet[e] = true
 	}
 
 	testFiles, err := os.ReadDir(testsDirectory)
 	if err != nil {
-		panic(err)
+		if !recordScanError(diags, &scanErrors, testsDirectory, err, strict) {
+			return nil, nil, nil, scanErrors
+		}
 	}
 
 	tflog.Info(ctx, "Source Filter: '"+sourceFilter+"'")
 
 	for _, file := range testFiles {
-		if !file.IsDir() && strings.Contains(file.Name(), filter) {
-			filepath := filepath.Join(testsDirectory, file.Name())
-			tflog.Info(ctx, filepath)
-			tests = append(tests, searchFile(filepath, sourceFilter, examplesDirectory, ctx)...)
+		if file.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(testsDirectory, file.Name())
+		isTFTest := strings.HasSuffix(file.Name(), ".tftest.hcl")
+
+		if isTFTest && testFormat != "legacy" {
+			runs, err := parseTFTestFile(path, examplesDirectory, mockFilter)
+			if err != nil {
+				if !recordScanError(diags, &scanErrors, path, err, strict) {
+					return nil, nil, nil, scanErrors
+				}
+				continue
+			}
+
+			for _, run := range runs {
+				if !exampleSet[run.example] {
+					continue
+				}
+				coverage[run.example] = append(coverage[run.example], run)
+			}
+
+			continue
+		}
+
+		if testFormat == "tftest" || !strings.Contains(file.Name(), filter) {
+			continue
+		}
+
+		tflog.Info(ctx, path)
+
+		var (
+			examplesFound []string
+			scanErr       error
+		)
+
+		if parserMode == "regex" {
+			examplesFound, scanErr = searchFile(path, sourceFilter, examplesDirectory, ctx)
+		} else {
+			examplesFound, scanErr = searchFileHCL(path, examplesDirectory)
+		}
+
+		if scanErr != nil {
+			if !recordScanError(diags, &scanErrors, path, scanErr, strict) {
+				return nil, nil, nil, scanErrors
+			}
+			continue
+		}
+
+		for _, example := range examplesFound {
+			if !exampleSet[example] {
+				continue
+			}
+			coverage[example] = append(coverage[example], tftestRunCoverage{example: example, file: path})
 		}
 	}
 
 	for _, e := range examples {
-		if !slices.Contains(tests, e) {
+		if _, ok := coverage[e]; !ok {
 			missing = append(missing, e)
 		}
 	}
 
-	return missing
+	return missing, coverage, examples, scanErrors
+}
+
+// recordScanError handles a failure to read path. In strict mode it is added
+// to diags as an error and the caller should stop scanning; otherwise it's
+// recorded as a warning and appended to scanErrors so the caller can skip
+// path and continue. The return value reports whether the caller should
+// continue.
+func recordScanError(diags *diag.Diagnostics, scanErrors *[]string, path string, err error, strict bool) bool {
+	message := fmt.Sprintf("%s: %s", path, err)
+
+	if strict {
+		diags.AddError("Unable to scan for example coverage", message)
+		return false
+	}
+
+	diags.AddWarning("Skipping unreadable path", message)
+	*scanErrors = append(*scanErrors, message)
+
+	return true
+}
+
+// parseTFTestFile parses a single native Terraform test file and returns the
+// coverage contributed by each of its run blocks whose module source resolves
+// inside examplesDirectory. mockFilter, when non-nil, restricts the
+// require_mocks check to providers whose name it matches.
+func parseTFTestFile(path string, examplesDirectory string, mockFilter *regexp.Regexp) ([]tftestRunCoverage, error) {
+	parser := hclparse.NewParser()
+
+	hclFile, diags := parser.ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, ok := hclFile.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse %q as native HCL syntax", path)
+	}
+
+	testDir := filepath.Dir(path)
+
+	var coverage []tftestRunCoverage
+
+	for _, block := range body.Blocks {
+		if block.Type != "run" || len(block.Labels) == 0 {
+			continue
+		}
+
+		runName := block.Labels[0]
+		hasMock := evaluateMockCoverage(body, block, mockFilter)
+
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "module" {
+				continue
+			}
+
+			source, ok := moduleBlockSource(inner)
+			if !ok {
+				continue
+			}
+
+			resolved := filepath.Clean(filepath.Join(testDir, source))
+
+			example, ok := exampleForPath(resolved, examplesDirectory)
+			if !ok {
+				continue
+			}
+
+			coverage = append(coverage, tftestRunCoverage{
+				example:    example,
+				file:       path,
+				runName:    runName,
+				hasMock:    hasMock,
+				fromTFTest: true,
+			})
+		}
+	}
+
+	return coverage, nil
+}
+
+// evaluateMockCoverage reports whether a run block satisfies require_mocks:
+// either the enclosing file declares a mock_provider block, or every provider
+// the run references (via its providers map, filtered by mockFilter) is
+// overridden by an override_resource/override_data block in the file.
+func evaluateMockCoverage(fileBody *hclsyntax.Body, run *hclsyntax.Block, mockFilter *regexp.Regexp) bool {
+	if blockOfType(fileBody.Blocks, "mock_provider") != nil {
+		return true
+	}
+
+	providers := referencedProviders(run, mockFilter)
+	if len(providers) == 0 {
+		return true
+	}
+
+	overridden := overriddenProviders(fileBody.Blocks)
+
+	for provider := range providers {
+		if !overridden[provider] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// blockOfType returns the first block of blockType found at any nesting depth.
+func blockOfType(blocks hclsyntax.Blocks, blockType string) *hclsyntax.Block {
+	for _, block := range blocks {
+		if block.Type == blockType {
+			return block
+		}
+
+		if found := blockOfType(block.Body.Blocks, blockType); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// referencedProviders returns the set of provider type names (e.g. "aws")
+// referenced by a run block's `providers` map, filtered by mockFilter when set.
+func referencedProviders(run *hclsyntax.Block, mockFilter *regexp.Regexp) map[string]bool {
+	providers := map[string]bool{}
+
+	providersAttr, ok := run.Body.Attributes["providers"]
+	if !ok {
+		return providers
+	}
+
+	obj, ok := providersAttr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return providers
+	}
+
+	for _, item := range obj.Items {
+		traversal, travDiags := hcl.AbsTraversalForExpr(item.ValueExpr)
+		if travDiags.HasErrors() || len(traversal) == 0 {
+			continue
+		}
+
+		name := traversal.RootName()
+		if mockFilter == nil || mockFilter.MatchString(name) {
+			providers[name] = true
+		}
+	}
+
+	return providers
+}
+
+// overriddenProviders returns the provider type names implied by the
+// resource/data addresses targeted by override_resource/override_data blocks,
+// at any nesting depth.
+func overriddenProviders(blocks hclsyntax.Blocks) map[string]bool {
+	overridden := map[string]bool{}
+
+	var walk func(blocks hclsyntax.Blocks)
+	walk = func(blocks hclsyntax.Blocks) {
+		for _, block := range blocks {
+			if block.Type == "override_resource" || block.Type == "override_data" {
+				if target, ok := block.Body.Attributes["target"]; ok {
+					if traversal, travDiags := hcl.AbsTraversalForExpr(target.Expr); !travDiags.HasErrors() && len(traversal) > 0 {
+						resourceType, ok := resourceTypeFromTarget(block.Type, traversal)
+						if ok {
+							if provider, ok := providerFromResourceType(resourceType); ok {
+								overridden[provider] = true
+							}
+						}
+					}
+				}
+			}
+
+			walk(block.Body.Blocks)
+		}
+	}
+
+	walk(blocks)
+
+	return overridden
+}
+
+// resourceTypeFromTarget extracts the resource/data source type name from an
+// override block's target traversal. An override_resource target looks like
+// <type>.<name>, so the type is the traversal's root. An override_data
+// target looks like data.<type>.<name>, so the type is the segment after the
+// leading "data".
+func resourceTypeFromTarget(blockType string, traversal hcl.Traversal) (string, bool) {
+	if blockType == "override_data" {
+		if len(traversal) < 2 {
+			return "", false
+		}
+
+		attr, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return "", false
+		}
+
+		return attr.Name, true
+	}
+
+	return traversal.RootName(), true
+}
+
+// providerFromResourceType returns the provider type implied by a resource or
+// data source type name, e.g. "aws" for "aws_instance".
+func providerFromResourceType(resourceType string) (string, bool) {
+	idx := strings.Index(resourceType, "_")
+	if idx <= 0 {
+		return "", false
+	}
+
+	return resourceType[:idx], true
+}
+
+// moduleBlockSource extracts the literal "source" attribute from a module block,
+// ignoring blocks whose source is not a static string.
+func moduleBlockSource(block *hclsyntax.Block) (string, bool) {
+	sourceAttr, ok := block.Body.Attributes["source"]
+	if !ok {
+		return "", false
+	}
+
+	val, diags := sourceAttr.Expr.Value(nil)
+	if diags.HasErrors() || val.Type() != cty.String {
+		return "", false
+	}
+
+	return val.AsString(), true
+}
+
+// exampleForPath returns the example identifier for a filesystem path, if that
+// path resolves inside examplesDirectory. The identifier is the path relative
+// to examplesDirectory, e.g. "aws/vpc/basic".
+func exampleForPath(path string, examplesDirectory string) (string, bool) {
+	absExamples, err := filepath.Abs(examplesDirectory)
+	if err != nil {
+		return "", false
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(absExamples, absPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+// discoverExamples walks examplesDirectory recursively and returns the
+// relative path of every directory that directly contains a .tf file,
+// treating that path as the example's canonical identifier. maxDepth bounds
+// how many directory levels below examplesDirectory are searched; 0 means
+// unlimited. A directory that can't be read is, per strict, either recorded
+// to scanErrors and skipped (continuing the walk) or treated as fatal; a
+// fatal error aborts the walk and is returned.
+func discoverExamples(examplesDirectory string, maxDepth int, strict bool, diags *diag.Diagnostics, scanErrors *[]string) ([]string, error) {
+	var examples []string
+
+	err := filepath.WalkDir(examplesDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !recordScanError(diags, scanErrors, path, err, strict) {
+				return err
+			}
+
+			return fs.SkipDir
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(examplesDirectory, path)
+		if err != nil {
+			if !recordScanError(diags, scanErrors, path, err, strict) {
+				return err
+			}
+
+			return fs.SkipDir
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		depth := len(strings.Split(filepath.ToSlash(rel), "/"))
+		if maxDepth > 0 && depth > maxDepth {
+			return fs.SkipDir
+		}
+
+		hasTF, err := containsTerraformFiles(path)
+		if err != nil {
+			if !recordScanError(diags, scanErrors, path, err, strict) {
+				return err
+			}
+
+			return fs.SkipDir
+		}
+
+		if hasTF {
+			examples = append(examples, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+
+	return examples, err
+}
+
+// containsTerraformFiles reports whether dir directly contains at least one
+// .tf file.
+func containsTerraformFiles(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".tf") {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
-func searchFile(file string, pattern string, examplesDirectory string, ctx context.Context) []string {
+func searchFile(file string, pattern string, examplesDirectory string, ctx context.Context) ([]string, error) {
 	var result []string
 
 	f, err := os.Open(file)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	defer f.Close()
 
+	fileDir := filepath.Dir(file)
+
 	r := regexp.MustCompile(`^\s*source\s*=\s*".+examples.+"$`)
 
 	scanner := bufio.NewScanner(f)
@@ -177,12 +741,59 @@ func searchFile(file string, pattern string, examplesDirectory string, ctx conte
 			matches := re.FindAllStringSubmatch(line, -1)
 
 			for _, match := range matches {
-				strippedText := filepath.Base(match[1])
-				tflog.Info(ctx, strippedText)
-				result = append(result, strippedText)
+				resolved := filepath.Clean(filepath.Join(fileDir, match[1]))
+
+				example, ok := exampleForPath(resolved, examplesDirectory)
+				if !ok {
+					continue
+				}
+
+				tflog.Info(ctx, example)
+				result = append(result, example)
 			}
 		}
 	}
 
-	return result
+	return result, nil
+}
+
+// searchFileHCL parses file as HCL and returns the example identifiers covered
+// by any module block, at any nesting depth, whose source attribute is a
+// literal string resolving inside examplesDirectory.
+func searchFileHCL(file string, examplesDirectory string) ([]string, error) {
+	parser := hclparse.NewParser()
+
+	hclFile, hclDiags := parser.ParseHCLFile(file)
+	if hclDiags.HasErrors() {
+		return nil, hclDiags
+	}
+
+	body, ok := hclFile.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("%q could not be parsed as native HCL syntax", file)
+	}
+
+	fileDir := filepath.Dir(file)
+
+	var result []string
+
+	var walk func(blocks hclsyntax.Blocks)
+	walk = func(blocks hclsyntax.Blocks) {
+		for _, block := range blocks {
+			if block.Type == "module" {
+				if source, ok := moduleBlockSource(block); ok {
+					resolved := filepath.Clean(filepath.Join(fileDir, source))
+					if example, ok := exampleForPath(resolved, examplesDirectory); ok {
+						result = append(result, example)
+					}
+				}
+			}
+
+			walk(block.Body.Blocks)
+		}
+	}
+
+	walk(body.Blocks)
+
+	return result, nil
 }
@@ -0,0 +1,292 @@
+// Copyright (c) Brendan Thompson
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// writeFile creates path (and any missing parent directories) under dir with
+// contents, returning the full path.
+func writeFile(t *testing.T, dir string, path string, contents string) string {
+	t.Helper()
+
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatalf("creating parent directory for %s: %s", full, err)
+	}
+
+	if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %s", full, err)
+	}
+
+	return full
+}
+
+func TestDiscoverExamples(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, dir, "aws/main.tf", `resource "aws_instance" "test" {}`)
+	writeFile(t, dir, "aws/vpc/main.tf", `resource "aws_vpc" "test" {}`)
+	writeFile(t, dir, "gcp/main.tf", `resource "google_compute_instance" "test" {}`)
+	writeFile(t, dir, "empty/readme.md", `not terraform`)
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		want     []string
+	}{
+		{name: "unlimited depth", maxDepth: 0, want: []string{"aws", "aws/vpc", "gcp"}},
+		{name: "depth bounded to 1", maxDepth: 1, want: []string{"aws", "gcp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			var scanErrors []string
+
+			got, err := discoverExamples(dir, tt.maxDepth, false, &diags, &scanErrors)
+			if err != nil {
+				t.Fatalf("discoverExamples() error = %s", err)
+			}
+
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("discoverExamples() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverExamplesMissingDirectory(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "does-not-exist")
+
+	t.Run("non-strict skips and records the error", func(t *testing.T) {
+		var diags diag.Diagnostics
+		var scanErrors []string
+
+		got, err := discoverExamples(missing, 0, false, &diags, &scanErrors)
+		if err != nil {
+			t.Fatalf("discoverExamples() error = %s, want nil", err)
+		}
+
+		if len(got) != 0 {
+			t.Errorf("discoverExamples() = %v, want none", got)
+		}
+
+		if len(scanErrors) != 1 {
+			t.Errorf("scanErrors = %v, want exactly one entry", scanErrors)
+		}
+	})
+
+	t.Run("strict aborts", func(t *testing.T) {
+		var diags diag.Diagnostics
+		var scanErrors []string
+
+		_, err := discoverExamples(missing, 0, true, &diags, &scanErrors)
+		if err == nil {
+			t.Fatal("discoverExamples() error = nil, want non-nil")
+		}
+
+		if !diags.HasError() {
+			t.Error("diags.HasError() = false, want true")
+		}
+	})
+}
+
+func TestOverriddenProviders(t *testing.T) {
+	src := `
+override_resource {
+  target = aws_instance.test
+}
+
+override_data {
+  target = data.aws_ami.test
+}
+`
+
+	parser := hclparse.NewParser()
+
+	f, diags := parser.ParseHCL([]byte(src), "overrides.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("ParseHCL() diags = %s", diags)
+	}
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		t.Fatal("parsed file body is not *hclsyntax.Body")
+	}
+
+	got := overriddenProviders(body.Blocks)
+
+	want := map[string]bool{"aws": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("overriddenProviders() = %v, want %v", got, want)
+	}
+}
+
+func TestParseTFTestFile(t *testing.T) {
+	dir := t.TempDir()
+	examplesDir := filepath.Join(dir, "examples")
+	testsDir := filepath.Join(dir, "tests")
+
+	tests := []struct {
+		name     string
+		hcl      string
+		wantMock bool
+	}{
+		{
+			name: "mock_provider covers every referenced provider",
+			hcl: `
+run "r" {
+  providers = {
+    aws = aws
+  }
+
+  module {
+    source = "../examples/aws"
+  }
+
+  mock_provider "aws" {}
+}
+`,
+			wantMock: true,
+		},
+		{
+			name: "no mock or override leaves the provider uncovered",
+			hcl: `
+run "r" {
+  providers = {
+    aws = aws
+  }
+
+  module {
+    source = "../examples/aws"
+  }
+}
+`,
+			wantMock: false,
+		},
+		{
+			name: "override_resource covers the referenced provider",
+			hcl: `
+run "r" {
+  providers = {
+    aws = aws
+  }
+
+  module {
+    source = "../examples/aws"
+  }
+
+  override_resource {
+    target = aws_instance.test
+  }
+}
+`,
+			wantMock: true,
+		},
+		{
+			name: "override_data covers the referenced provider",
+			hcl: `
+run "r" {
+  providers = {
+    aws = aws
+  }
+
+  module {
+    source = "../examples/aws"
+  }
+
+  override_data {
+    target = data.aws_ami.test
+  }
+}
+`,
+			wantMock: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, testsDir, fmt.Sprintf("case%d.tftest.hcl", i), tt.hcl)
+
+			runs, err := parseTFTestFile(path, examplesDir, nil)
+			if err != nil {
+				t.Fatalf("parseTFTestFile() error = %s", err)
+			}
+
+			if len(runs) != 1 {
+				t.Fatalf("parseTFTestFile() returned %d runs, want 1", len(runs))
+			}
+
+			run := runs[0]
+
+			if run.example != "aws" {
+				t.Errorf("run.example = %q, want \"aws\"", run.example)
+			}
+
+			if !run.fromTFTest {
+				t.Error("run.fromTFTest = false, want true")
+			}
+
+			if run.hasMock != tt.wantMock {
+				t.Errorf("run.hasMock = %v, want %v", run.hasMock, tt.wantMock)
+			}
+		})
+	}
+}
+
+func TestFindMissingTests(t *testing.T) {
+	dir := t.TempDir()
+	examplesDir := filepath.Join(dir, "examples")
+	testsDir := filepath.Join(dir, "tests")
+
+	writeFile(t, examplesDir, "aws/main.tf", `resource "aws_instance" "test" {}`)
+	writeFile(t, examplesDir, "gcp/main.tf", `resource "google_compute_instance" "test" {}`)
+
+	writeFile(t, testsDir, "aws.tftest.hcl", `
+run "basic" {
+  module {
+    source = "../examples/aws/submodule"
+  }
+}
+`)
+
+	var diags diag.Diagnostics
+
+	missing, coverage, examples, scanErrors := findMissingTests(examplesDir, testsDir, "", "auto", "hcl", 0, nil, false, context.Background(), &diags)
+	if diags.HasError() {
+		t.Fatalf("findMissingTests() diags = %s", diags)
+	}
+
+	if len(scanErrors) != 0 {
+		t.Fatalf("scanErrors = %v, want none", scanErrors)
+	}
+
+	slices.Sort(examples)
+	if !slices.Equal(examples, []string{"aws", "gcp"}) {
+		t.Fatalf("examples = %v, want [aws gcp]", examples)
+	}
+
+	if _, ok := coverage["aws/submodule"]; ok {
+		t.Error(`coverage contains "aws/submodule", a path discoverExamples never found`)
+	}
+
+	slices.Sort(missing)
+	want := []string{"aws", "gcp"}
+	if !slices.Equal(missing, want) {
+		t.Errorf("missing = %v, want %v (a run targeting a non-example subdirectory covers nothing)", missing, want)
+	}
+}
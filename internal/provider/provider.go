@@ -48,7 +48,9 @@ func (p *CoverageProvider) Configure(ctx context.Context, req provider.Configure
 }
 
 func (p *CoverageProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewCoverageReportResource,
+	}
 }
 
 func (p *CoverageProvider) DataSources(ctx context.Context) []func() datasource.DataSource {